@@ -0,0 +1,91 @@
+package vanish
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// AliasFilterField is the message field an AliasFilter matches against.
+type AliasFilterField string
+
+const (
+	FilterFrom    AliasFilterField = "from"
+	FilterSubject AliasFilterField = "subject"
+)
+
+// AliasAction decides what happens to an inbound message that matches an
+// AliasFilter.
+type AliasAction string
+
+const (
+	ActionForward   AliasAction = "forward"
+	ActionDrop      AliasAction = "drop"
+	ActionAutoReply AliasAction = "auto_reply"
+)
+
+// AliasFilter is a predicate evaluated against an inbound message that
+// decides whether it is forwarded, dropped, or auto-replied to.
+type AliasFilter struct {
+	Field   AliasFilterField `json:"field"`
+	Pattern string           `json:"pattern"`
+	Regex   bool             `json:"regex"`
+	Action  AliasAction      `json:"action"`
+}
+
+// AliasOpts are options for creating or updating an alias.
+type AliasOpts struct {
+	Address   string        `json:"address"`
+	ForwardTo string        `json:"forwardTo"`
+	Filters   []AliasFilter `json:"filters,omitempty"`
+}
+
+// Alias is a stable address that fronts one or more rotating real
+// mailboxes, forwarding, dropping, or auto-replying to inbound messages
+// according to its Filters.
+type Alias struct {
+	ID        string        `json:"id"`
+	Address   string        `json:"address"`
+	ForwardTo string        `json:"forwardTo"`
+	Filters   []AliasFilter `json:"filters"`
+}
+
+// CreateAlias registers a new alias.
+func (c *Client) CreateAlias(ctx context.Context, opts AliasOpts) (*Alias, error) {
+	var result Alias
+	if err := c.doJSON(ctx, http.MethodPost, "/aliases", opts, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListAliases returns all aliases owned by the caller.
+func (c *Client) ListAliases(ctx context.Context) ([]Alias, error) {
+	var resp struct {
+		Data []Alias `json:"data"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, "/aliases", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// UpdateAlias replaces the forwarding target and filters of an existing
+// alias.
+func (c *Client) UpdateAlias(ctx context.Context, aliasID string, opts AliasOpts) (*Alias, error) {
+	var result Alias
+	path := "/aliases/" + url.PathEscape(aliasID)
+	if err := c.doJSON(ctx, http.MethodPut, path, opts, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteAlias removes an alias by ID.
+func (c *Client) DeleteAlias(ctx context.Context, aliasID string) error {
+	var resp struct {
+		Success bool `json:"success"`
+	}
+	path := "/aliases/" + url.PathEscape(aliasID)
+	return c.doJSON(ctx, http.MethodDelete, path, nil, &resp)
+}
@@ -0,0 +1,113 @@
+// Package imapgw exposes the mailboxes of a *vanish.Client as a standard
+// IMAP server, so disposable inboxes can be read from Thunderbird, mutt, or
+// any other client that speaks IMAP instead of the Vanish HTTP API.
+package imapgw
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/coldpatch/vanish-go"
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/backend"
+)
+
+// Backend adapts a *vanish.Client to the go-imap backend.Backend interface.
+// Every IMAP username maps to a Vanish mailbox address; the password field
+// is unused since Vanish authenticates via the client's API key.
+type Backend struct {
+	client  *vanish.Client
+	updates chan backend.Update
+}
+
+// New returns an IMAP backend backed by client. Serve it with
+// github.com/emersion/go-imap/server.
+func New(client *vanish.Client) *Backend {
+	return &Backend{
+		client:  client,
+		updates: make(chan backend.Update, 16),
+	}
+}
+
+// Updates implements backend.BackendUpdater, letting go-imap/server push
+// unsolicited EXISTS updates to clients that sent IDLE when new mail
+// arrives. Each Mailbox feeds this channel from a background poll loop
+// started the first time it's looked up.
+func (b *Backend) Updates() <-chan backend.Update {
+	return b.updates
+}
+
+// Login implements backend.Backend. Any password is accepted; the username
+// is the Vanish mailbox address to expose.
+func (b *Backend) Login(_ *imap.ConnInfo, username, _ string) (backend.User, error) {
+	return &User{backend: b, address: username}, nil
+}
+
+// User is the IMAP view of a single Vanish mailbox address. Vanish has no
+// concept of nested mailboxes, so it is always exposed as a single INBOX.
+type User struct {
+	backend *Backend
+	address string
+
+	mu      sync.Mutex
+	mailbox *Mailbox
+}
+
+// Username implements backend.User.
+func (u *User) Username() string {
+	return u.address
+}
+
+// ListMailboxes implements backend.User.
+func (u *User) ListMailboxes(_ bool) ([]backend.Mailbox, error) {
+	mbox, err := u.inbox()
+	if err != nil {
+		return nil, err
+	}
+	return []backend.Mailbox{mbox}, nil
+}
+
+// GetMailbox implements backend.User.
+func (u *User) GetMailbox(name string) (backend.Mailbox, error) {
+	if !strings.EqualFold(name, "INBOX") {
+		return nil, fmt.Errorf("imapgw: no such mailbox %q", name)
+	}
+	return u.inbox()
+}
+
+// CreateMailbox implements backend.User. Vanish mailboxes are created via
+// vanish.Client.GenerateEmail, not through IMAP, so this always fails.
+func (u *User) CreateMailbox(string) error {
+	return fmt.Errorf("imapgw: creating mailboxes is not supported, use vanish.Client.GenerateEmail")
+}
+
+// DeleteMailbox implements backend.User.
+func (u *User) DeleteMailbox(name string) error {
+	if !strings.EqualFold(name, "INBOX") {
+		return fmt.Errorf("imapgw: no such mailbox %q", name)
+	}
+	_, err := u.backend.client.DeleteMailbox(context.Background(), u.address)
+	return err
+}
+
+// RenameMailbox implements backend.User.
+func (u *User) RenameMailbox(string, string) error {
+	return fmt.Errorf("imapgw: renaming mailboxes is not supported")
+}
+
+// Logout implements backend.User.
+func (u *User) Logout() error {
+	return nil
+}
+
+func (u *User) inbox() (*Mailbox, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.mailbox == nil {
+		u.mailbox = &Mailbox{user: u}
+		u.mailbox.startWatching()
+	}
+	return u.mailbox, nil
+}
@@ -0,0 +1,594 @@
+package imapgw
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coldpatch/vanish-go"
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/backend"
+)
+
+// pollInterval is how often the background watch loop polls the Vanish API
+// for new mail via vanish.Client.PollForEmails, both to detect activity for
+// IDLE and to keep the UID mapping fresh.
+const pollInterval = 10 * time.Second
+
+// pollTimeout bounds how long a single PollForEmails call blocks waiting
+// for new mail before the watch loop re-syncs and polls again.
+const pollTimeout = time.Minute
+
+// Mailbox is the sole mailbox (INBOX) exposed for a Vanish address. UIDs
+// are assigned locally, in arrival order, since Vanish email IDs are
+// opaque strings rather than monotonic integers.
+type Mailbox struct {
+	user *User
+
+	watchOnce sync.Once
+
+	mu        sync.Mutex
+	uids      []uint32                       // sequence number -> UID, in IMAP message-sequence order
+	ids       map[uint32]string              // UID -> Vanish email ID
+	summaries map[uint32]vanish.EmailSummary // UID -> last synced summary, for SEARCH
+	nextUID   uint32
+	deleted   map[uint32]bool // UID -> pending \Deleted flag
+	seen      map[uint32]bool // UID -> \Seen flag
+}
+
+var _ backend.Mailbox = (*Mailbox)(nil)
+
+// Name implements backend.Mailbox.
+func (m *Mailbox) Name() string {
+	return "INBOX"
+}
+
+// Info implements backend.Mailbox.
+func (m *Mailbox) Info() (*imap.MailboxInfo, error) {
+	return &imap.MailboxInfo{
+		Delimiter: "/",
+		Name:      "INBOX",
+	}, nil
+}
+
+// Status implements backend.Mailbox.
+func (m *Mailbox) Status(items []imap.StatusItem) (*imap.MailboxStatus, error) {
+	if err := m.sync(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.statusLocked(items), nil
+}
+
+func (m *Mailbox) statusLocked(items []imap.StatusItem) *imap.MailboxStatus {
+	status := imap.NewMailboxStatus("INBOX", items)
+	status.Flags = []string{imap.DeletedFlag, imap.SeenFlag}
+	status.PermanentFlags = []string{imap.DeletedFlag, imap.SeenFlag}
+	status.UidValidity = 1
+
+	for _, item := range items {
+		switch item {
+		case imap.StatusMessages:
+			status.Messages = uint32(len(m.uids))
+		case imap.StatusUidNext:
+			status.UidNext = m.nextUID
+		case imap.StatusUnseen:
+			var unseen uint32
+			for _, u := range m.uids {
+				if !m.seen[u] {
+					unseen++
+				}
+			}
+			status.Unseen = unseen
+		}
+	}
+	return status
+}
+
+// SetSubscribed implements backend.Mailbox. Subscription state isn't
+// tracked since INBOX is the only mailbox.
+func (m *Mailbox) SetSubscribed(bool) error {
+	return nil
+}
+
+// Check implements backend.Mailbox.
+func (m *Mailbox) Check() error {
+	return m.sync()
+}
+
+// ListMessages implements backend.Mailbox, serving FETCH requests.
+func (m *Mailbox) ListMessages(uid bool, seqSet *imap.SeqSet, items []imap.FetchItem, ch chan<- *imap.Message) error {
+	defer close(ch)
+
+	if err := m.sync(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	seqNums, uids := m.matchLocked(uid, seqSet)
+	m.mu.Unlock()
+
+	for i, seqNum := range seqNums {
+		u := uids[i]
+		emailID := m.emailID(u)
+		msg, err := m.toIMAPMessage(context.Background(), seqNum, u, emailID, items, m.flagsFor(u))
+		if err != nil {
+			return err
+		}
+		ch <- msg
+	}
+	return nil
+}
+
+// SearchMessages implements backend.Mailbox, matching FROM/SUBJECT header
+// criteria and SEEN/DELETED flag criteria against the synced mailbox
+// state. Criteria this gateway can't evaluate without fetching every
+// message body (BODY/TEXT searches, OR, NOT) are rejected rather than
+// silently treated as a match.
+func (m *Mailbox) SearchMessages(uid bool, criteria *imap.SearchCriteria) ([]uint32, error) {
+	if err := m.sync(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var out []uint32
+	for i, u := range m.uids {
+		seqNum := uint32(i + 1)
+		ok, err := matchesCriteria(seqNum, u, m.summaries[u], m.seen[u], m.deleted[u], criteria)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if uid {
+			out = append(out, u)
+		} else {
+			out = append(out, seqNum)
+		}
+	}
+	return out, nil
+}
+
+// matchesCriteria reports whether a message matches c. It returns an error
+// for criteria that would require fetching message bodies or boolean
+// combinators this gateway doesn't implement, rather than matching
+// everything.
+func matchesCriteria(seqNum, uid uint32, summary vanish.EmailSummary, seen, deleted bool, c *imap.SearchCriteria) (bool, error) {
+	if c == nil {
+		return true, nil
+	}
+	if c.SeqNum != nil && !c.SeqNum.Contains(seqNum) {
+		return false, nil
+	}
+	if c.Uid != nil && !c.Uid.Contains(uid) {
+		return false, nil
+	}
+	if !c.Since.IsZero() && summary.ReceivedAt.Before(c.Since) {
+		return false, nil
+	}
+	if !c.Before.IsZero() && !summary.ReceivedAt.Before(c.Before) {
+		return false, nil
+	}
+
+	for key, values := range c.Header {
+		var field string
+		switch strings.ToLower(key) {
+		case "from":
+			field = summary.From
+		case "subject":
+			field = summary.Subject
+		default:
+			return false, fmt.Errorf("imapgw: unsupported SEARCH header %q", key)
+		}
+		matched := false
+		for _, v := range values {
+			if v == "" || strings.Contains(strings.ToLower(field), strings.ToLower(v)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+
+	for _, flag := range c.WithFlags {
+		ok, err := hasFlag(flag, seen, deleted)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	for _, flag := range c.WithoutFlags {
+		ok, err := hasFlag(flag, seen, deleted)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return false, nil
+		}
+	}
+
+	if len(c.Body) > 0 || len(c.Text) > 0 {
+		return false, fmt.Errorf("imapgw: SEARCH BODY/TEXT is not supported")
+	}
+	if len(c.Or) > 0 || len(c.Not) > 0 {
+		return false, fmt.Errorf("imapgw: SEARCH OR/NOT is not supported")
+	}
+	return true, nil
+}
+
+func hasFlag(flag string, seen, deleted bool) (bool, error) {
+	switch flag {
+	case imap.SeenFlag:
+		return seen, nil
+	case imap.DeletedFlag:
+		return deleted, nil
+	default:
+		return false, fmt.Errorf("imapgw: unsupported SEARCH flag %q", flag)
+	}
+}
+
+// CreateMessage implements backend.Mailbox. Vanish mailboxes only receive
+// mail from the outside world, so appending a message via IMAP isn't
+// supported.
+func (m *Mailbox) CreateMessage(_ []string, _ time.Time, _ imap.Literal) error {
+	return fmt.Errorf("imapgw: APPEND is not supported")
+}
+
+// UpdateMessagesFlags implements backend.Mailbox. Only \Seen and \Deleted
+// are tracked; \Deleted stages messages for removal on EXPUNGE.
+func (m *Mailbox) UpdateMessagesFlags(uid bool, seqSet *imap.SeqSet, operation imap.FlagsOp, flags []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, uids := m.matchLocked(uid, seqSet)
+	for _, u := range uids {
+		if containsFlag(flags, imap.DeletedFlag) {
+			applyFlag(m.deleted, u, operation)
+		}
+		if containsFlag(flags, imap.SeenFlag) {
+			applyFlag(m.seen, u, operation)
+		}
+	}
+	return nil
+}
+
+func applyFlag(set map[uint32]bool, uid uint32, operation imap.FlagsOp) {
+	switch operation {
+	case imap.SetFlags, imap.AddFlags:
+		set[uid] = true
+	case imap.RemoveFlags:
+		delete(set, uid)
+	}
+}
+
+// CopyMessages implements backend.Mailbox. INBOX is the only mailbox, so
+// there is never a valid copy destination.
+func (m *Mailbox) CopyMessages(bool, *imap.SeqSet, string) error {
+	return fmt.Errorf("imapgw: COPY is not supported, INBOX is the only mailbox")
+}
+
+// Expunge implements backend.Mailbox, permanently removing messages staged
+// with \Deleted via vanish.Client.DeleteEmail.
+func (m *Mailbox) Expunge() error {
+	m.mu.Lock()
+	toDelete := make([]uint32, 0, len(m.deleted))
+	for u := range m.deleted {
+		toDelete = append(toDelete, u)
+	}
+	m.mu.Unlock()
+
+	for _, u := range toDelete {
+		emailID := m.emailID(u)
+		if err := m.user.backend.client.DeleteEmail(context.Background(), emailID); err != nil {
+			return fmt.Errorf("imapgw: expunge %s: %w", emailID, err)
+		}
+		m.mu.Lock()
+		delete(m.deleted, u)
+		m.mu.Unlock()
+	}
+	return m.sync()
+}
+
+// sync refreshes the sequence-number/UID mapping and cached summaries from
+// the Vanish API.
+func (m *Mailbox) sync() error {
+	list, err := m.user.backend.client.ListEmails(context.Background(), m.user.address, nil)
+	if err != nil {
+		return fmt.Errorf("imapgw: list emails: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.ids == nil {
+		m.ids = make(map[uint32]string)
+		m.summaries = make(map[uint32]vanish.EmailSummary)
+		m.deleted = make(map[uint32]bool)
+		m.seen = make(map[uint32]bool)
+		m.nextUID = 1
+	}
+
+	byID := make(map[string]uint32, len(m.ids))
+	for u, id := range m.ids {
+		byID[id] = u
+	}
+
+	m.uids = m.uids[:0]
+	for i := len(list.Data) - 1; i >= 0; i-- {
+		email := list.Data[i]
+		u, ok := byID[email.ID]
+		if !ok {
+			u = m.nextUID
+			m.nextUID++
+			m.ids[u] = email.ID
+		}
+		m.summaries[u] = email
+		m.uids = append(m.uids, u)
+	}
+	return nil
+}
+
+// startWatching starts, once per Mailbox, a background loop that polls the
+// Vanish API for new mail and feeds backend.MailboxUpdates to the
+// Backend's Updates() channel so go-imap/server can push them to clients
+// that sent IDLE.
+func (m *Mailbox) startWatching() {
+	m.watchOnce.Do(func() {
+		go m.watchLoop()
+	})
+}
+
+func (m *Mailbox) watchLoop() {
+	count := -1
+	for {
+		if err := m.sync(); err == nil {
+			m.mu.Lock()
+			n := len(m.uids)
+			status := m.statusLocked([]imap.StatusItem{imap.StatusMessages, imap.StatusUidNext})
+			m.mu.Unlock()
+
+			if count >= 0 && n != count {
+				update := &backend.MailboxUpdate{
+					Update:        backend.NewUpdate(m.user.address, "INBOX"),
+					MailboxStatus: status,
+				}
+				select {
+				case m.user.backend.updates <- update:
+				default: // drop if nobody's listening; the next sync will catch up
+				}
+			}
+			count = n
+		}
+
+		// PollForEmails blocks (up to pollTimeout) until new mail arrives,
+		// so IDLE'ing clients see updates promptly without busy-polling. A
+		// transient error (network blip, server hiccup) shouldn't kill the
+		// watch loop for the rest of the process, so log it and back off
+		// before retrying instead of returning.
+		if _, err := m.user.backend.client.PollForEmails(context.Background(), m.user.address, pollTimeout, pollInterval, count); err != nil {
+			log.Printf("imapgw: poll %s: %v", m.user.address, err)
+			time.Sleep(pollInterval)
+		}
+	}
+}
+
+func (m *Mailbox) emailID(uid uint32) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ids[uid]
+}
+
+func (m *Mailbox) flagsFor(uid uint32) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var flags []string
+	if m.seen[uid] {
+		flags = append(flags, imap.SeenFlag)
+	}
+	if m.deleted[uid] {
+		flags = append(flags, imap.DeletedFlag)
+	}
+	return flags
+}
+
+func (m *Mailbox) matchLocked(uid bool, seqSet *imap.SeqSet) (seqNums, uids []uint32) {
+	for i, u := range m.uids {
+		seqNum := uint32(i + 1)
+		matched := seqSet.Contains(seqNum)
+		if uid {
+			matched = seqSet.Contains(u)
+		}
+		if matched {
+			seqNums = append(seqNums, seqNum)
+			uids = append(uids, u)
+		}
+	}
+	return seqNums, uids
+}
+
+func containsFlag(flags []string, want string) bool {
+	for _, f := range flags {
+		if strings.EqualFold(f, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// toIMAPMessage builds an *imap.Message for emailID, reconstructing real
+// MIME structure from the raw RFC 5322 source (via vanish.Client.GetEmailRaw
+// and vanish.ParseEmail) whenever the requested items need it, rather than
+// synthesizing a fake text/plain structure from EmailDetail's pre-rendered
+// HTML/text split.
+func (m *Mailbox) toIMAPMessage(ctx context.Context, seqNum, uid uint32, emailID string, items []imap.FetchItem, flags []string) (*imap.Message, error) {
+	msg := imap.NewMessage(seqNum, items)
+	msg.Uid = uid
+
+	var needsRaw, needsDetail bool
+	for _, item := range items {
+		switch item {
+		case imap.FetchRFC822Size, imap.FetchBody, imap.FetchBodyStructure:
+			needsRaw = true
+		case imap.FetchEnvelope, imap.FetchInternalDate:
+			needsDetail = true
+		default:
+			if strings.HasPrefix(string(item), "BODY[") {
+				needsRaw = true
+			}
+		}
+	}
+
+	var raw []byte
+	var parsed *vanish.ParsedMessage
+	if needsRaw {
+		rc, err := m.user.backend.client.GetEmailRaw(ctx, emailID)
+		if err != nil {
+			return nil, fmt.Errorf("imapgw: fetch raw %s: %w", emailID, err)
+		}
+		raw, err = io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("imapgw: read raw %s: %w", emailID, err)
+		}
+		parsed, err = vanish.ParseEmail(bytes.NewReader(raw))
+		if err != nil {
+			return nil, fmt.Errorf("imapgw: parse %s: %w", emailID, err)
+		}
+	}
+
+	var detail *vanish.EmailDetail
+	if needsDetail {
+		var err error
+		detail, err = m.user.backend.client.GetEmail(ctx, emailID)
+		if err != nil {
+			return nil, fmt.Errorf("imapgw: fetch %s: %w", emailID, err)
+		}
+	}
+
+	for _, item := range items {
+		switch item {
+		case imap.FetchEnvelope:
+			msg.Envelope = &imap.Envelope{
+				Date:    detail.ReceivedAt,
+				Subject: detail.Subject,
+				From:    parseAddressList(detail.From),
+				To:      parseAddressListAll(detail.To),
+			}
+		case imap.FetchFlags:
+			msg.Flags = flags
+		case imap.FetchInternalDate:
+			msg.InternalDate = detail.ReceivedAt
+		case imap.FetchRFC822Size:
+			msg.Size = uint32(len(raw))
+		case imap.FetchBody, imap.FetchBodyStructure:
+			msg.BodyStructure = buildBodyStructure(parsed.Parts)
+		default:
+			if strings.HasPrefix(string(item), "BODY[") {
+				sec, err := imap.ParseBodySectionName(item)
+				if err != nil {
+					return nil, fmt.Errorf("imapgw: parse %s: %w", item, err)
+				}
+				msg.Body[sec] = bytes.NewBuffer(bodySection(raw, parsed, item))
+			}
+		}
+	}
+	return msg, nil
+}
+
+// buildBodyStructure reflects parts as an imap.BodyStructure: a single
+// leaf for a plain message, or a multipart/mixed structure with one leaf
+// per part otherwise.
+func buildBodyStructure(parts []vanish.MessagePart) *imap.BodyStructure {
+	if len(parts) == 1 {
+		return leafBodyStructure(parts[0])
+	}
+	bs := &imap.BodyStructure{MIMEType: "multipart", MIMESubType: "mixed"}
+	for _, p := range parts {
+		bs.Parts = append(bs.Parts, leafBodyStructure(p))
+	}
+	return bs
+}
+
+func leafBodyStructure(p vanish.MessagePart) *imap.BodyStructure {
+	mimeType, mimeSubType := "text", "plain"
+	if i := strings.IndexByte(p.ContentType, '/'); i >= 0 {
+		mimeType, mimeSubType = p.ContentType[:i], p.ContentType[i+1:]
+	}
+	bs := &imap.BodyStructure{
+		MIMEType:    mimeType,
+		MIMESubType: mimeSubType,
+		Size:        uint32(len(p.Body)),
+	}
+	if p.Filename != "" {
+		bs.Disposition = "attachment"
+		bs.DispositionParams = map[string]string{"filename": p.Filename}
+	}
+	return bs
+}
+
+// bodySection extracts the bytes for a BODY[<section>] fetch item from the
+// raw message source: the whole message for BODY[], just the headers for
+// BODY[HEADER], just the body for BODY[TEXT], or a specific MIME part's
+// decoded body for BODY[<n>].
+func bodySection(raw []byte, parsed *vanish.ParsedMessage, item imap.FetchItem) []byte {
+	spec := strings.TrimSuffix(strings.TrimPrefix(string(item), "BODY["), "]")
+	switch spec {
+	case "":
+		return raw
+	case "HEADER":
+		header, _ := splitHeaderBody(raw)
+		return header
+	case "TEXT":
+		_, body := splitHeaderBody(raw)
+		return body
+	default:
+		if n, err := strconv.Atoi(spec); err == nil && parsed != nil && n >= 1 && n <= len(parsed.Parts) {
+			return parsed.Parts[n-1].Body
+		}
+		return raw
+	}
+}
+
+func splitHeaderBody(raw []byte) (header, body []byte) {
+	if i := bytes.Index(raw, []byte("\r\n\r\n")); i >= 0 {
+		return raw[:i+2], raw[i+4:]
+	}
+	if i := bytes.Index(raw, []byte("\n\n")); i >= 0 {
+		return raw[:i+1], raw[i+2:]
+	}
+	return raw, nil
+}
+
+func parseAddressList(raw string) []*imap.Address {
+	if raw == "" {
+		return nil
+	}
+	mailbox, host := raw, ""
+	if i := strings.LastIndexByte(raw, '@'); i >= 0 {
+		mailbox, host = raw[:i], raw[i+1:]
+	}
+	return []*imap.Address{{MailboxName: mailbox, HostName: host}}
+}
+
+func parseAddressListAll(raws []string) []*imap.Address {
+	addrs := make([]*imap.Address, 0, len(raws))
+	for _, raw := range raws {
+		addrs = append(addrs, parseAddressList(raw)...)
+	}
+	return addrs
+}
@@ -0,0 +1,170 @@
+package vanish
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/http"
+	"net/mail"
+	"strings"
+)
+
+// GetEmailRaw returns the raw RFC 5322 source of an email, including
+// headers (DKIM-Signature, Received, List-Unsubscribe, etc.) stripped from
+// EmailDetail. The caller is responsible for closing the returned reader.
+func (c *Client) GetEmailRaw(ctx context.Context, emailID string) (io.ReadCloser, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/email/"+emailID+"/raw", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		return nil, c.errorFromResponse(resp)
+	}
+
+	return resp.Body, nil
+}
+
+// MessagePart is a single part of a parsed MIME message. Inline parts carry
+// body content directly; attachment parts carry a filename. Nested
+// multipart parts (e.g. multipart/alternative inside multipart/mixed) are
+// flattened into the same list as their parent.
+type MessagePart struct {
+	Header      mail.Header
+	ContentType string
+	Filename    string
+	Attachment  bool
+	Body        []byte
+}
+
+// ParsedMessage is an RFC 5322 message decoded from raw MIME source.
+type ParsedMessage struct {
+	Header mail.Header
+	Parts  []MessagePart
+}
+
+// ParseEmail parses the raw MIME source read from r into headers and a flat
+// list of inline and attachment parts, decoding Q/B encoded header words
+// along the way. It is built on net/mail and mime/multipart so callers can
+// run their own MIME logic against the original source rather than the
+// server's pre-rendered HTML/text split.
+func ParseEmail(r io.Reader) (*ParsedMessage, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("vanish: read message: %w", err)
+	}
+	header := decodeHeader(msg.Header)
+
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", nil
+	}
+
+	parsed := &ParsedMessage{Header: header}
+
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		body, err := decodeBody(msg.Body, header.Get("Content-Transfer-Encoding"))
+		if err != nil {
+			return nil, fmt.Errorf("vanish: read body: %w", err)
+		}
+		parsed.Parts = []MessagePart{{
+			Header:      header,
+			ContentType: mediaType,
+			Body:        body,
+		}}
+		return parsed, nil
+	}
+
+	parts, err := parseMultipartParts(msg.Body, params["boundary"])
+	if err != nil {
+		return nil, err
+	}
+	parsed.Parts = parts
+	return parsed, nil
+}
+
+// parseMultipartParts walks the parts of a multipart body, recursing into
+// any part that is itself multipart (e.g. a multipart/alternative nested
+// inside a multipart/mixed) and flattening the result.
+func parseMultipartParts(r io.Reader, boundary string) ([]MessagePart, error) {
+	mr := multipart.NewReader(r, boundary)
+	var parts []MessagePart
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("vanish: read part: %w", err)
+		}
+
+		body, err := decodeBody(part, part.Header.Get("Content-Transfer-Encoding"))
+		if err != nil {
+			return nil, fmt.Errorf("vanish: decode part: %w", err)
+		}
+
+		partType, partParams, err := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if err != nil {
+			partType = "text/plain"
+		}
+
+		if strings.HasPrefix(partType, "multipart/") {
+			nested, err := parseMultipartParts(bytes.NewReader(body), partParams["boundary"])
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, nested...)
+			continue
+		}
+
+		filename := part.FileName()
+		parts = append(parts, MessagePart{
+			Header:      decodeHeader(mail.Header(part.Header)),
+			ContentType: partType,
+			Filename:    filename,
+			Attachment:  filename != "",
+			Body:        body,
+		})
+	}
+
+	return parts, nil
+}
+
+func decodeBody(r io.Reader, encoding string) ([]byte, error) {
+	switch strings.ToLower(encoding) {
+	case "quoted-printable":
+		return io.ReadAll(quotedprintable.NewReader(r))
+	case "base64":
+		return io.ReadAll(base64.NewDecoder(base64.StdEncoding, r))
+	default:
+		return io.ReadAll(r)
+	}
+}
+
+// decodeHeader returns a copy of h with every value run through a
+// mime.WordDecoder, so RFC 2047 encoded words (=?utf-8?Q?...?=) in
+// headers like Subject and From come back as plain text. Values that fail
+// to decode are left as-is.
+func decodeHeader(h mail.Header) mail.Header {
+	dec := new(mime.WordDecoder)
+	out := make(mail.Header, len(h))
+	for key, values := range h {
+		decoded := make([]string, len(values))
+		for i, v := range values {
+			if d, err := dec.DecodeHeader(v); err == nil {
+				decoded[i] = d
+			} else {
+				decoded[i] = v
+			}
+		}
+		out[key] = decoded
+	}
+	return out
+}
@@ -0,0 +1,174 @@
+package vanish
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures automatic retries for doRequest.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values less than 1 disable retries.
+	MaxAttempts int
+
+	// Backoff is the base delay used for exponential backoff between
+	// attempts when the server doesn't send a Retry-After header.
+	// Defaults to 200ms.
+	Backoff time.Duration
+
+	// RetryOn lists the HTTP status codes that trigger a retry. Defaults
+	// to 429 and 503.
+	RetryOn []int
+}
+
+// WithRetry enables automatic retries on doRequest, honoring Retry-After on
+// 429/503 responses and falling back to exponential backoff with jitter.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+func (p RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+func (p RetryPolicy) retryableStatus(status int) bool {
+	retryOn := p.RetryOn
+	if len(retryOn) == 0 {
+		retryOn = []int{http.StatusTooManyRequests, http.StatusServiceUnavailable}
+	}
+	for _, s := range retryOn {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the exponential-backoff-with-jitter delay before the
+// given (zero-indexed) retry attempt.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	base := p.Backoff
+	if base <= 0 {
+		base = 200 * time.Millisecond
+	}
+	d := base << attempt
+	return d + jitter(d/2)
+}
+
+// jitter returns a random duration in [0, max).
+func jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return 0
+	}
+	return time.Duration(binary.BigEndian.Uint64(b[:]) % uint64(max))
+}
+
+// sleep waits for d, or returns false early if ctx is cancelled first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// retryAfter parses a Retry-After header, which per RFC 9110 is either a
+// number of seconds or an HTTP date. It returns 0 if the header is absent
+// or unparseable.
+func retryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// RateLimitInfo is the most recently observed rate-limit state reported by
+// the server via X-RateLimit-Remaining/X-RateLimit-Reset headers.
+type RateLimitInfo struct {
+	Remaining int
+	Reset     time.Time
+}
+
+// RateLimitInfo returns the rate-limit state from the most recent response
+// that carried rate-limit headers, or a zero value if the server hasn't sent
+// them yet.
+func (c *Client) RateLimitInfo() RateLimitInfo {
+	c.rateLimitMu.Lock()
+	defer c.rateLimitMu.Unlock()
+	return c.rateLimit
+}
+
+// parseRateLimit extracts rate-limit metadata from a response's headers.
+// It returns the zero value if the server didn't send any.
+func parseRateLimit(header http.Header) RateLimitInfo {
+	var info RateLimitInfo
+	if n, err := strconv.Atoi(header.Get("X-RateLimit-Remaining")); err == nil {
+		info.Remaining = n
+	}
+	if n, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		info.Reset = time.Unix(n, 0)
+	}
+	return info
+}
+
+func (c *Client) recordRateLimit(header http.Header) {
+	if header.Get("X-RateLimit-Remaining") == "" && header.Get("X-RateLimit-Reset") == "" {
+		return
+	}
+
+	info := parseRateLimit(header)
+	c.rateLimitMu.Lock()
+	c.rateLimit = info
+	c.rateLimitMu.Unlock()
+}
+
+// newIdempotencyKey returns a random UUIDv4 string suitable for use as an
+// Idempotency-Key header, so retried POST/DELETE calls are safe to repeat
+// on the server.
+func newIdempotencyKey() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant 10
+
+	buf := make([]byte, 36)
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:36], b[10:16])
+	return string(buf)
+}
+
+func needsIdempotencyKey(method string) bool {
+	return method == http.MethodPost || method == http.MethodDelete
+}
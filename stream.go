@@ -0,0 +1,256 @@
+package vanish
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StreamEventType identifies the kind of event delivered on an EmailEvent
+// channel returned by Stream.
+type StreamEventType string
+
+const (
+	StreamEmailReceived  StreamEventType = "EMAIL_RECEIVED"
+	StreamEmailDeleted   StreamEventType = "EMAIL_DELETED"
+	StreamMailboxExpired StreamEventType = "MAILBOX_EXPIRED"
+)
+
+// EmailEvent is a single event observed on a mailbox address. Sequence is
+// monotonically increasing per address and can be saved by the caller to
+// resume a dropped stream via StreamOpts.LastEventID.
+type EmailEvent struct {
+	Type     StreamEventType
+	Sequence int64
+	Email    *EmailSummary // set for StreamEmailReceived and StreamEmailDeleted
+}
+
+// StreamOpts configures Stream.
+type StreamOpts struct {
+	// LastEventID resumes a previously interrupted stream from the given
+	// sequence number, mirroring SSE's Last-Event-ID semantics.
+	LastEventID string
+
+	// PollInterval is the starting interval used for the adaptive polling
+	// fallback when the server doesn't support SSE. Defaults to 2s.
+	PollInterval time.Duration
+
+	// MaxPollInterval caps how far PollInterval backs off to when the
+	// mailbox is quiet. Defaults to 30s.
+	MaxPollInterval time.Duration
+}
+
+// Stream delivers EmailEvents for address as they happen. It first attempts
+// the server's SSE endpoint (GET /mailbox/{addr}/stream with
+// Accept: text/event-stream) and transparently falls back to adaptive
+// polling when the server responds 404 or 415, so callers get the same
+// channel-based API either way.
+//
+// The polling fallback can only observe new mail: it emits
+// StreamEmailReceived but never StreamEmailDeleted or StreamMailboxExpired,
+// since those require the server's own event feed. Callers that need those
+// event types depend on SSE support being present.
+//
+// Both returned channels are closed when ctx is cancelled. The error
+// channel receives at most one error before closing.
+func (c *Client) Stream(ctx context.Context, address string, opts StreamOpts) (<-chan EmailEvent, <-chan error) {
+	events := make(chan EmailEvent)
+	errs := make(chan error, 1)
+
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 2 * time.Second
+	}
+	if opts.MaxPollInterval <= 0 {
+		opts.MaxPollInterval = 30 * time.Second
+	}
+
+	go func() {
+		defer close(events)
+		defer close(errs)
+
+		resp, err := c.openSSE(ctx, address, opts.LastEventID)
+		if err != nil {
+			if ctx.Err() == nil {
+				errs <- err
+			}
+			return
+		}
+
+		if resp != nil {
+			defer resp.Body.Close()
+			if err := readSSE(ctx, resp.Body, events); err != nil && ctx.Err() == nil {
+				errs <- err
+			}
+			return
+		}
+
+		if err := c.pollFallback(ctx, address, opts, events); err != nil && ctx.Err() == nil {
+			errs <- err
+		}
+	}()
+
+	return events, errs
+}
+
+// openSSE attempts the server's streaming endpoint. A nil response with a
+// nil error means the server doesn't support SSE (404/415) and the caller
+// should fall back to polling.
+func (c *Client) openSSE(ctx context.Context, address, lastEventID string) (*http.Response, error) {
+	path := "/mailbox/" + url.PathEscape(address) + "/stream"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("vanish: create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnsupportedMediaType {
+		resp.Body.Close()
+		return nil, nil
+	}
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		return nil, c.errorFromResponse(resp)
+	}
+	return resp, nil
+}
+
+// readSSE decodes a text/event-stream body into EmailEvents until ctx is
+// cancelled or the stream ends.
+func readSSE(ctx context.Context, body io.Reader, events chan<- EmailEvent) error {
+	scanner := bufio.NewScanner(body)
+	var dataLines []string
+
+	flush := func() error {
+		if len(dataLines) == 0 {
+			return nil
+		}
+		data := strings.Join(dataLines, "\n")
+		dataLines = dataLines[:0]
+
+		var payload struct {
+			Type     StreamEventType `json:"type"`
+			Sequence int64           `json:"sequence"`
+			Email    *EmailSummary   `json:"email"`
+		}
+		if err := json.Unmarshal([]byte(data), &payload); err != nil {
+			return fmt.Errorf("vanish: decode stream event: %w", err)
+		}
+
+		select {
+		case events <- EmailEvent{Type: payload.Type, Sequence: payload.Sequence, Email: payload.Email}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		return nil
+	}
+
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if err := flush(); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "data:"):
+			dataLines = append(dataLines, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	return flush()
+}
+
+// pollFallback emulates Stream with adaptive-interval polling for servers
+// without SSE support, backing off toward opts.MaxPollInterval when the
+// mailbox is quiet and resetting to opts.PollInterval on any activity.
+//
+// It only ever emits StreamEmailReceived: unlike the SSE path, polling
+// ListEmails gives no signal for deletions or mailbox expiry, so
+// StreamEmailDeleted and StreamMailboxExpired are never produced here.
+//
+// LastEventID only seeds the sequence counter, since the fallback has no
+// durable record of which email IDs were already delivered under past
+// sequence numbers. To avoid re-delivering the whole mailbox on start or
+// resume, it takes a baseline snapshot of the mailbox's current contents
+// before polling and treats everything in it as already seen.
+func (c *Client) pollFallback(ctx context.Context, address string, opts StreamOpts, events chan<- EmailEvent) error {
+	interval := opts.PollInterval
+	var sequence int64
+	if opts.LastEventID != "" {
+		if n, err := strconv.ParseInt(opts.LastEventID, 10, 64); err == nil {
+			sequence = n
+		}
+	}
+
+	seen := make(map[string]bool)
+	baseline, err := c.ListEmails(ctx, address, &ListEmailsOpts{Limit: 20})
+	if err != nil {
+		return err
+	}
+	for _, email := range baseline.Data {
+		seen[email.ID] = true
+	}
+
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		result, err := c.ListEmails(ctx, address, &ListEmailsOpts{Limit: 20})
+		if err != nil {
+			return err
+		}
+
+		activity := false
+		for i := len(result.Data) - 1; i >= 0; i-- {
+			email := result.Data[i]
+			if seen[email.ID] {
+				continue
+			}
+			seen[email.ID] = true
+			sequence++
+			activity = true
+
+			e := email
+			select {
+			case events <- EmailEvent{Type: StreamEmailReceived, Sequence: sequence, Email: &e}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if activity {
+			interval = opts.PollInterval
+		} else if interval < opts.MaxPollInterval {
+			interval *= 2
+			if interval > opts.MaxPollInterval {
+				interval = opts.MaxPollInterval
+			}
+		}
+		timer.Reset(interval)
+	}
+}
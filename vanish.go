@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
 	"time"
 )
 
@@ -17,6 +18,11 @@ type Client struct {
 	baseURL    string
 	apiKey     string
 	httpClient *http.Client
+
+	retryPolicy RetryPolicy
+
+	rateLimitMu sync.Mutex
+	rateLimit   RateLimitInfo
 }
 
 // Option configures the client.
@@ -61,6 +67,12 @@ func NewClient(baseURL string, opts ...Option) *Client {
 type Error struct {
 	Message    string
 	StatusCode int
+
+	// RateLimitRemaining and RateLimitReset carry the X-RateLimit-Remaining
+	// and X-RateLimit-Reset headers from the response that produced this
+	// error, if the server sent them.
+	RateLimitRemaining int
+	RateLimitReset     time.Time
 }
 
 func (e *Error) Error() string {
@@ -117,27 +129,73 @@ type ListEmailsOpts struct {
 	Cursor string
 }
 
+// doRequest sends a single logical request, retrying on the status codes
+// in c.retryPolicy.RetryOn (honoring Retry-After, falling back to
+// exponential backoff with jitter) and attaching a stable Idempotency-Key
+// to POST/DELETE calls so retries are safe for the server to deduplicate.
 func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
-	var reqBody io.Reader
+	var data []byte
 	if body != nil {
-		data, err := json.Marshal(body)
+		var err error
+		data, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("vanish: marshal body: %w", err)
 		}
-		reqBody = bytes.NewReader(data)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("vanish: create request: %w", err)
+	var idempotencyKey string
+	if needsIdempotencyKey(method) {
+		idempotencyKey = newIdempotencyKey()
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	if c.apiKey != "" {
-		req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	}
+	attempts := c.retryPolicy.maxAttempts()
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		var reqBody io.Reader
+		if data != nil {
+			reqBody = bytes.NewReader(data)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("vanish: create request: %w", err)
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+		if c.apiKey != "" {
+			req.Header.Set("Authorization", "Bearer "+c.apiKey)
+		}
+		if idempotencyKey != "" {
+			req.Header.Set("Idempotency-Key", idempotencyKey)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			lastErr = err
+			if attempt == attempts-1 || !sleep(ctx, c.retryPolicy.backoff(attempt)) {
+				return nil, err
+			}
+			continue
+		}
 
-	return c.httpClient.Do(req)
+		c.recordRateLimit(resp.Header)
+
+		if attempt < attempts-1 && c.retryPolicy.retryableStatus(resp.StatusCode) {
+			wait := retryAfter(resp.Header.Get("Retry-After"))
+			if wait <= 0 {
+				wait = c.retryPolicy.backoff(attempt)
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			if !sleep(ctx, wait) {
+				return nil, ctx.Err()
+			}
+			continue
+		}
+
+		return resp, nil
+	}
+	return nil, lastErr
 }
 
 func (c *Client) doJSON(ctx context.Context, method, path string, body, result interface{}) error {
@@ -148,13 +206,7 @@ func (c *Client) doJSON(ctx context.Context, method, path string, body, result i
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		var errResp struct {
-			Error string `json:"error"`
-		}
-		if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
-			return &Error{Message: http.StatusText(resp.StatusCode), StatusCode: resp.StatusCode}
-		}
-		return &Error{Message: errResp.Error, StatusCode: resp.StatusCode}
+		return c.errorFromResponse(resp)
 	}
 
 	if result != nil {
@@ -165,6 +217,27 @@ func (c *Client) doJSON(ctx context.Context, method, path string, body, result i
 	return nil
 }
 
+// errorFromResponse builds an *Error from a >=400 response body, attaching
+// whatever rate-limit metadata resp itself carried. It does not close
+// resp.Body.
+func (c *Client) errorFromResponse(resp *http.Response) *Error {
+	limit := parseRateLimit(resp.Header)
+	apiErr := &Error{
+		Message:            http.StatusText(resp.StatusCode),
+		StatusCode:         resp.StatusCode,
+		RateLimitRemaining: limit.Remaining,
+		RateLimitReset:     limit.Reset,
+	}
+
+	var errResp struct {
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&errResp); err == nil && errResp.Error != "" {
+		apiErr.Message = errResp.Error
+	}
+	return apiErr
+}
+
 // GetDomains returns the list of available email domains.
 func (c *Client) GetDomains(ctx context.Context) ([]string, error) {
 	var resp struct {
@@ -230,13 +303,7 @@ func (c *Client) GetAttachment(ctx context.Context, emailID, attachmentID string
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		var errResp struct {
-			Error string `json:"error"`
-		}
-		if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
-			return nil, nil, &Error{Message: http.StatusText(resp.StatusCode), StatusCode: resp.StatusCode}
-		}
-		return nil, nil, &Error{Message: errResp.Error, StatusCode: resp.StatusCode}
+		return nil, nil, c.errorFromResponse(resp)
 	}
 
 	content, err := io.ReadAll(resp.Body)
@@ -268,6 +335,9 @@ func (c *Client) DeleteMailbox(ctx context.Context, address string) (int, error)
 
 // PollForEmails waits for a new email to arrive up to the given timeout.
 // It returns the first new email if one arrives, or nil if timeout is reached.
+//
+// Deprecated: use Stream, which prefers server-sent events over polling and
+// reports typed events instead of just the newest email.
 func (c *Client) PollForEmails(ctx context.Context, address string, timeout, interval time.Duration, initialCount int) (*EmailSummary, error) {
 	deadline := time.Now().Add(timeout)
 	ticker := time.NewTicker(interval)
@@ -0,0 +1,145 @@
+package vanish
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// WebhookEventType identifies the kind of event a webhook subscription
+// should be notified about.
+type WebhookEventType string
+
+const (
+	EventEmailReceived WebhookEventType = "EMAIL_RECEIVED"
+	EventEmailOpened   WebhookEventType = "EMAIL_OPENED"
+)
+
+// WebhookOpts are options for registering a new webhook subscription.
+type WebhookOpts struct {
+	URL        string             `json:"url"`
+	EventTypes []WebhookEventType `json:"eventTypes"`
+	Secret     string             `json:"secret"`
+}
+
+// Webhook is a registered webhook subscription against a mailbox.
+type Webhook struct {
+	ID         string             `json:"id"`
+	Address    string             `json:"address"`
+	URL        string             `json:"url"`
+	EventTypes []WebhookEventType `json:"eventTypes"`
+}
+
+// EmailReceivedPayload is the body of an EMAIL_RECEIVED webhook event.
+type EmailReceivedPayload struct {
+	Address string       `json:"address"`
+	Email   EmailSummary `json:"email"`
+}
+
+// EmailOpenedPayload is the body of an EMAIL_OPENED webhook event, sent when
+// a recipient views an email that was instrumented with open tracking.
+type EmailOpenedPayload struct {
+	Address string       `json:"address"`
+	Email   EmailSummary `json:"email"`
+}
+
+// CreateWebhook registers a new webhook subscription against a mailbox
+// address.
+func (c *Client) CreateWebhook(ctx context.Context, address string, opts WebhookOpts) (*Webhook, error) {
+	path := "/mailbox/" + url.PathEscape(address) + "/webhooks"
+	var result Webhook
+	if err := c.doJSON(ctx, http.MethodPost, path, opts, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListWebhooks returns the webhook subscriptions registered against a
+// mailbox address.
+func (c *Client) ListWebhooks(ctx context.Context, address string) ([]Webhook, error) {
+	path := "/mailbox/" + url.PathEscape(address) + "/webhooks"
+	var resp struct {
+		Data []Webhook `json:"data"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// DeleteWebhook removes a webhook subscription by ID.
+func (c *Client) DeleteWebhook(ctx context.Context, webhookID string) error {
+	var resp struct {
+		Success bool `json:"success"`
+	}
+	return c.doJSON(ctx, http.MethodDelete, "/webhooks/"+webhookID, nil, &resp)
+}
+
+// NewWebhookHandler returns an http.Handler that verifies the
+// X-Vanish-Signature header against secret using HMAC-SHA256, decodes the
+// typed event payload, and invokes the matching callback.
+//
+// onReceived is called with an EmailReceivedPayload for EMAIL_RECEIVED
+// events and onOpened with an EmailOpenedPayload for EMAIL_OPENED events.
+// Either callback may be nil to ignore that event type; other event types
+// are acknowledged but otherwise ignored.
+func NewWebhookHandler(secret string, onReceived func(EmailReceivedPayload), onOpened func(EmailOpenedPayload)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "vanish: read body", http.StatusBadRequest)
+			return
+		}
+
+		if !verifyWebhookSignature(secret, body, r.Header.Get("X-Vanish-Signature")) {
+			http.Error(w, "vanish: invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		var envelope struct {
+			Type    WebhookEventType `json:"type"`
+			Payload json.RawMessage  `json:"payload"`
+		}
+		if err := json.Unmarshal(body, &envelope); err != nil {
+			http.Error(w, "vanish: decode event", http.StatusBadRequest)
+			return
+		}
+
+		switch envelope.Type {
+		case EventEmailReceived:
+			if onReceived == nil {
+				break
+			}
+			var payload EmailReceivedPayload
+			if err := json.Unmarshal(envelope.Payload, &payload); err != nil {
+				http.Error(w, "vanish: decode payload", http.StatusBadRequest)
+				return
+			}
+			onReceived(payload)
+		case EventEmailOpened:
+			if onOpened == nil {
+				break
+			}
+			var payload EmailOpenedPayload
+			if err := json.Unmarshal(envelope.Payload, &payload); err != nil {
+				http.Error(w, "vanish: decode payload", http.StatusBadRequest)
+				return
+			}
+			onOpened(payload)
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func verifyWebhookSignature(secret string, body []byte, signature string) bool {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}